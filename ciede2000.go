@@ -25,43 +25,78 @@ import (
 // to be in radians, not degrees. In Excel, use the RADIANS function to convert
 // degrees to radians.
 
-// kL=kC=kH=1 under reference conditions
-// Illumination: D65 source
+// Distance computes the CIEDE2000 color difference under reference
+// conditions (kL=kC=kH=1) and the sRGB/D65 assumption. It is a thin wrapper
+// around DistanceWithParams.
 func Distance(c1, c2 color.Color) float64 {
+	return DistanceWithParams(c1, c2, GraphicArtsParams())
+}
+
+// DistanceWithParams computes the CIEDE2000 color difference between c1 and
+// c2 using the parametric weighting factors in p, for viewing conditions
+// that depart from the CIE reference conditions.
+func DistanceWithParams(c1, c2 color.Color, p Params) float64 {
 	l1 := toLAB(c1)
 	l2 := toLAB(c2)
 
+	return deltaE2000(l1, l2, p)
+}
+
+// radians converts degrees to radians.
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// degrees converts radians to degrees.
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// deltaE2000 computes the CIEDE2000 color difference between two already
+// converted L*a*b* values. It is the core of Distance, factored out so
+// Converter-based conversions (see NewDistance) can reuse it.
+//
+// All angles below (hPrime1, hPrime2, hBarPrime, deltaTheta) are in degrees,
+// per the CIEDE2000 formulas; they're converted to radians immediately
+// before being handed to math.Sin/math.Cos.
+func deltaE2000(l1, l2 Lab, p Params) float64 {
 	// Calculate C'_i, h'_i
-	cStar1 := math.Sqrt((l1.a * l1.a) + (l1.b * l1.b))
-	cStar2 := math.Sqrt((l2.a * l2.a) + (l2.b * l2.b))
+	cStar1 := math.Sqrt((l1.A * l1.A) + (l1.B * l1.B))
+	cStar2 := math.Sqrt((l2.A * l2.A) + (l2.B * l2.B))
 
 	cBar := (cStar1 + cStar2) / 2
 
 	g := 0.5 * (1 - math.Sqrt(math.Pow(cBar, 7)/(math.Pow(cBar, 7)+math.Pow(25, 7))))
 
-	aPrime1 := (1 + g) * l1.a
-	aPrime2 := (1 + g) * l2.a
+	aPrime1 := (1 + g) * l1.A
+	aPrime2 := (1 + g) * l2.A
 
-	cPrime1 := math.Sqrt((aPrime1 * aPrime1) + (l1.b * l1.b))
-	cPrime2 := math.Sqrt((aPrime2 * aPrime2) + (l2.b * l2.b))
+	cPrime1 := math.Sqrt((aPrime1 * aPrime1) + (l1.B * l1.B))
+	cPrime2 := math.Sqrt((aPrime2 * aPrime2) + (l2.B * l2.B))
 
 	var hPrime1 float64
 
-	if l1.b == 0 && aPrime1 == 0 {
+	if l1.B == 0 && aPrime1 == 0 {
 		hPrime1 = 0
 	} else {
-		hPrime1 = math.Atan2(l1.b, aPrime1) // are these in the right order?
+		hPrime1 = degrees(math.Atan2(l1.B, aPrime1))
+		if hPrime1 < 0 {
+			hPrime1 += 360
+		}
 	}
 
 	var hPrime2 float64
 
-	if l2.b == 0 && aPrime2 == 0 {
+	if l2.B == 0 && aPrime2 == 0 {
 		hPrime2 = 0
 	} else {
-		hPrime2 = math.Atan2(l2.b, aPrime2)
+		hPrime2 = degrees(math.Atan2(l2.B, aPrime2))
+		if hPrime2 < 0 {
+			hPrime2 += 360
+		}
 	}
 
-	deltaL := l2.l - l1.l
+	deltaL := l2.L - l1.L
 	deltaC := cPrime2 - cPrime1
 
 	var deltaH float64
@@ -76,9 +111,9 @@ func Distance(c1, c2 color.Color) float64 {
 		deltaH = (hPrime2 - hPrime1) + 360
 	}
 
-	deltaH = 2 * math.Sqrt(cPrime1*cPrime2) * math.Sin(deltaH/2)
+	deltaH = 2 * math.Sqrt(cPrime1*cPrime2) * math.Sin(radians(deltaH)/2)
 
-	lBarPrime := (l1.l + l2.l) / 2
+	lBarPrime := (l1.L + l2.L) / 2
 	cBarPrime := (cPrime1 + cPrime2) / 2
 
 	var hBarPrime float64
@@ -93,114 +128,35 @@ func Distance(c1, c2 color.Color) float64 {
 		hBarPrime = ((hPrime1 + hPrime2) - 360) / 2
 	}
 
-	t := 1 - (0.17 * math.Cos(hBarPrime-30)) + (0.24 * math.Cos(2*hBarPrime)) + (0.32 * math.Cos(3*hBarPrime+6)) - (0.20 * math.Cos(4*hBarPrime-63))
+	t := 1 - (0.17 * math.Cos(radians(hBarPrime-30))) + (0.24 * math.Cos(radians(2*hBarPrime))) + (0.32 * math.Cos(radians(3*hBarPrime+6))) - (0.20 * math.Cos(radians(4*hBarPrime-63)))
 
 	deltaTheta := 30 * math.Exp(-(((hBarPrime - 275) / 25) * ((hBarPrime - 275) / 25)))
 
-	rC := 2 * math.Sqrt(math.Pow(cBarPrime, 7)/(math.Pow(cBarPrime, 7)*math.Pow(25, 7)))
+	rC := 2 * math.Sqrt(math.Pow(cBarPrime, 7)/(math.Pow(cBarPrime, 7)+math.Pow(25, 7)))
 
 	// Positional corrections to the lack of uniformity
 	sL := 1 + (0.015 * ((lBarPrime - 50) * (lBarPrime - 50))) / (math.Sqrt(20 + ((lBarPrime - 50) * (lBarPrime - 50))))
 	sC := 1 + (0.045*cBarPrime)
 	sH := 1 + (0.015*cBarPrime*t)
-	rT := math.Asin(2 * deltaTheta) * rC
+	rT := -math.Sin(radians(2*deltaTheta)) * rC
 
 	// Corrections accounting for the influence of experimental viewing conditions
-	kL := 1.0
-	kC := 1.0
-	kH := 1.0
-
-	deltaL /= kL * sL
-	deltaC /= kC * sC
-	deltaH /= kH * sH
+	deltaL /= p.KL * sL
+	deltaC /= p.KC * sC
+	deltaH /= p.KH * sH
 
 	deltaE := math.Sqrt((deltaL * deltaL) + (deltaC * deltaC) + (deltaH * deltaH) + (rT * deltaC * deltaH))
 
 	return deltaE
 }
 
-type xyz struct {
-	x float64
-	y float64
-	z float64
+// toXYZ converts c to XYZ assuming the package default of sRGB/D65.
+func toXYZ(c color.Color) XYZ {
+	r, g, b := RGBToLinear(c)
+	return LinearToXYZ(r, g, b)
 }
 
-// TODO: explain what XYZ is
-// http://www.easyrgb.com/en/math.php
-func toXYZ(c color.Color) xyz {
-	sR, sG, sB, _ := c.RGBA()
-	r, g, b := float64(sR), float64(sG), float64(sB)
-
-	r /= 255 // not 255, but 65k?
-	g /= 255
-	b /= 255
-
-	// TODO: breakout into function? What even is this?
-	if r > 0.04045 {
-		r = math.Pow((r+0.055)/1.055, 2.4)
-	} else {
-		r /= 12.92
-	}
-
-	if g > 0.04045 {
-		g = math.Pow((g+0.055)/1.055, 2.4)
-	} else {
-		g /= 12.92
-	}
-
-	if b > 0.04045 {
-		b = math.Pow((b+0.055)/1.055, 2.4)
-	} else {
-		b /= 12.92
-	}
-
-	r *= 100
-	g *= 100
-	b *= 100
-
-	return xyz{
-		x: (r * 0.4124) + (g * 0.3576) + (b * 0.1805),
-		y: (r * 0.2126) + (g * 0.7152) + (b * 0.0722),
-		z: (r * 0.0193) + (g * 0.1192) + (b * 0.9505),
-	}
-}
-
-type lab struct {
-	l float64
-	a float64
-	b float64
-}
-
-func toLAB(c color.Color) lab {
-	xyz := toXYZ(c)
-
-	// using D65 illuminant
-	x := xyz.x / 95.047
-	y := xyz.y / 100.000
-	z := xyz.z / 108.883
-
-	// TODO: breakout into function
-	if x > 0.008856 {
-		x = math.Pow(x, 1/3)
-	} else {
-		x = (x * 7.787) + (16 / 116)
-	}
-
-	if y > 0.008856 {
-		y = math.Pow(y, 1/3)
-	} else {
-		y = (y * 7.787) + (16 / 116)
-	}
-
-	if z > 0.008856 {
-		z = math.Pow(z, 1/3)
-	} else {
-		z = (z * 7.787) + (16 / 116)
-	}
-
-	return lab{
-		l: (116 * y) - 16,
-		a: 500 * (x - y),
-		b: 200 * (y - z),
-	}
+// toLAB converts c to L*a*b* assuming the package default of sRGB/D65.
+func toLAB(c color.Color) Lab {
+	return XYZToLab(toXYZ(c), D65)
 }
@@ -0,0 +1,326 @@
+package ciede2000
+
+import (
+	"image/color"
+	"math"
+)
+
+// XYZ is the CIE 1931 XYZ color space, the linear space nearly every other
+// color space in this package is defined in terms of. Y is scaled to 100
+// for a reference white, not 1.
+type XYZ struct {
+	X, Y, Z float64
+}
+
+// Lab is the CIE L*a*b* color space: L* is lightness (0-100), a* and b*
+// are the green-red and blue-yellow opponent axes.
+type Lab struct {
+	L, A, B float64
+}
+
+// LCh is Lab expressed in cylindrical coordinates: C* is chroma and H is
+// hue, in degrees, normalized to [0, 360).
+type LCh struct {
+	L, C, H float64
+}
+
+// HSL is the classic hue/saturation/lightness cylindrical representation of
+// sRGB. H is in degrees [0, 360); S and L are in [0, 1]. Unlike Lab/LCh,
+// HSL is not a perceptually uniform space.
+type HSL struct {
+	H, S, L float64
+}
+
+// RGBToLinear converts c to linear-light sRGB components in [0, 1],
+// removing the sRGB transfer function (gamma).
+func RGBToLinear(c color.Color) (r, g, b float64) {
+	sR, sG, sB, _ := c.RGBA()
+
+	r = srgbCompand(float64(sR) / 65535.0)
+	g = srgbCompand(float64(sG) / 65535.0)
+	b = srgbCompand(float64(sB) / 65535.0)
+
+	return r, g, b
+}
+
+// LinearToRGB re-applies the sRGB transfer function to linear-light
+// components in [0, 1] and returns the corresponding opaque color.
+func LinearToRGB(r, g, b float64) color.Color {
+	clamp8 := func(v float64) uint8 {
+		v = srgbInvCompand(v)
+		if v < 0 {
+			return 0
+		}
+		if v > 1 {
+			return 255
+		}
+		return uint8(v*255 + 0.5)
+	}
+
+	return color.NRGBA{R: clamp8(r), G: clamp8(g), B: clamp8(b), A: 255}
+}
+
+// srgbToXYZInv is the inverse of SRGB.Matrix, computed once at package
+// initialization; later changes to SRGB.Matrix are not reflected in it.
+var srgbToXYZInv = invert3x3(SRGB.Matrix)
+
+// LinearToXYZ converts linear-light sRGB components in [0, 1] to XYZ
+// relative to D65 (Y scaled to 100).
+func LinearToXYZ(r, g, b float64) XYZ {
+	r *= 100
+	g *= 100
+	b *= 100
+
+	m := SRGB.Matrix
+	return XYZ{
+		X: (r * m[0][0]) + (g * m[0][1]) + (b * m[0][2]),
+		Y: (r * m[1][0]) + (g * m[1][1]) + (b * m[1][2]),
+		Z: (r * m[2][0]) + (g * m[2][1]) + (b * m[2][2]),
+	}
+}
+
+// XYZToLinear converts XYZ relative to D65 (Y scaled to 100) back to
+// linear-light sRGB components in [0, 1].
+func XYZToLinear(v XYZ) (r, g, b float64) {
+	inv := srgbToXYZInv
+
+	r = (v.X*inv[0][0] + v.Y*inv[0][1] + v.Z*inv[0][2]) / 100
+	g = (v.X*inv[1][0] + v.Y*inv[1][1] + v.Z*inv[1][2]) / 100
+	b = (v.X*inv[2][0] + v.Y*inv[2][1] + v.Z*inv[2][2]) / 100
+
+	return r, g, b
+}
+
+func invert3x3(m [3][3]float64) [3][3]float64 {
+	det := m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+
+	return [3][3]float64{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det,
+		},
+	}
+}
+
+// labF is the forward L*a*b* companding function applied to each
+// white-point-normalized XYZ component.
+func labF(t float64) float64 {
+	if t > 0.008856 {
+		return math.Pow(t, 1.0/3.0)
+	}
+	return (t * 7.787) + (16.0 / 116.0)
+}
+
+// labFInv is the inverse of labF.
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > 0.008856 {
+		return t3
+	}
+	return (t - 16.0/116.0) / 7.787
+}
+
+// XYZToLab converts v to L*a*b* relative to the given reference white
+// point.
+func XYZToLab(v XYZ, wp WhitePoint) Lab {
+	x := labF(v.X / wp.X)
+	y := labF(v.Y / wp.Y)
+	z := labF(v.Z / wp.Z)
+
+	return Lab{
+		L: (116 * y) - 16,
+		A: 500 * (x - y),
+		B: 200 * (y - z),
+	}
+}
+
+// LabToXYZ converts l to XYZ relative to the given reference white point.
+func LabToXYZ(l Lab, wp WhitePoint) XYZ {
+	y := (l.L + 16) / 116
+	x := l.A/500 + y
+	z := y - l.B/200
+
+	return XYZ{
+		X: labFInv(x) * wp.X,
+		Y: labFInv(y) * wp.Y,
+		Z: labFInv(z) * wp.Z,
+	}
+}
+
+// LabToLCh converts Lab to its cylindrical LCh representation.
+func LabToLCh(l Lab) LCh {
+	h := degrees(math.Atan2(l.B, l.A))
+	if h < 0 {
+		h += 360
+	}
+
+	return LCh{
+		L: l.L,
+		C: math.Sqrt(l.A*l.A + l.B*l.B),
+		H: h,
+	}
+}
+
+// LChToLab converts an LCh value back to Lab.
+func LChToLab(c LCh) Lab {
+	hr := radians(c.H)
+	return Lab{
+		L: c.L,
+		A: c.C * math.Cos(hr),
+		B: c.C * math.Sin(hr),
+	}
+}
+
+// RGBToHSL converts c to HSL.
+func RGBToHSL(c color.Color) HSL {
+	sR, sG, sB, _ := c.RGBA()
+	r := float64(sR) / 65535.0
+	g := float64(sG) / 65535.0
+	b := float64(sB) / 65535.0
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+
+	if max == min {
+		return HSL{H: 0, S: 0, L: l}
+	}
+
+	d := max - min
+
+	var s float64
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+
+	var h float64
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+
+	return HSL{H: h, S: s, L: l}
+}
+
+func hueToChannel(p, q, t float64) float64 {
+	if t < 0 {
+		t++
+	}
+	if t > 1 {
+		t--
+	}
+	switch {
+	case t < 1.0/6.0:
+		return p + (q-p)*6*t
+	case t < 1.0/2.0:
+		return q
+	case t < 2.0/3.0:
+		return p + (q-p)*(2.0/3.0-t)*6
+	default:
+		return p
+	}
+}
+
+// HSLToRGB converts an HSL value back to an opaque color.
+func HSLToRGB(c HSL) color.Color {
+	if c.S == 0 {
+		v := uint8(c.L*255 + 0.5)
+		return color.NRGBA{R: v, G: v, B: v, A: 255}
+	}
+
+	var q float64
+	if c.L < 0.5 {
+		q = c.L * (1 + c.S)
+	} else {
+		q = c.L + c.S - c.L*c.S
+	}
+	p := 2*c.L - q
+
+	h := c.H / 360
+
+	r := hueToChannel(p, q, h+1.0/3.0)
+	g := hueToChannel(p, q, h)
+	b := hueToChannel(p, q, h-1.0/3.0)
+
+	return color.NRGBA{
+		R: uint8(r*255 + 0.5),
+		G: uint8(g*255 + 0.5),
+		B: uint8(b*255 + 0.5),
+		A: 255,
+	}
+}
+
+// RGBA implements color.Color, converting v back to sRGB via the D65
+// default white point.
+func (v XYZ) RGBA() (r, g, b, a uint32) {
+	return LinearToRGB(XYZToLinear(v)).RGBA()
+}
+
+// RGBA implements color.Color, converting l back to sRGB assuming D65.
+func (l Lab) RGBA() (r, g, b, a uint32) {
+	return LabToXYZ(l, D65).RGBA()
+}
+
+// RGBA implements color.Color, converting c back to sRGB assuming D65.
+func (c LCh) RGBA() (r, g, b, a uint32) {
+	return LChToLab(c).RGBA()
+}
+
+// RGBA implements color.Color.
+func (c HSL) RGBA() (r, g, b, a uint32) {
+	return HSLToRGB(c).RGBA()
+}
+
+// XYZModel converts arbitrary colors to XYZ.
+var XYZModel = color.ModelFunc(func(c color.Color) color.Color {
+	if v, ok := c.(XYZ); ok {
+		return v
+	}
+	return toXYZ(c)
+})
+
+// LabModel converts arbitrary colors to Lab.
+var LabModel = color.ModelFunc(func(c color.Color) color.Color {
+	if l, ok := c.(Lab); ok {
+		return l
+	}
+	return toLAB(c)
+})
+
+// LChModel converts arbitrary colors to LCh.
+var LChModel = color.ModelFunc(func(c color.Color) color.Color {
+	if l, ok := c.(LCh); ok {
+		return l
+	}
+	return LabToLCh(toLAB(c))
+})
+
+// HSLModel converts arbitrary colors to HSL.
+var HSLModel = color.ModelFunc(func(c color.Color) color.Color {
+	if h, ok := c.(HSL); ok {
+		return h
+	}
+	return RGBToHSL(c)
+})
@@ -0,0 +1,85 @@
+package ciede2000
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestLabXYZRoundTrip(t *testing.T) {
+	want := Lab{L: 55.3, A: 12.7, B: -34.1}
+
+	got := XYZToLab(LabToXYZ(want, D65), D65)
+
+	if !almostEqual(got.L, want.L, 1e-6) || !almostEqual(got.A, want.A, 1e-6) || !almostEqual(got.B, want.B, 1e-6) {
+		t.Errorf("XYZToLab(LabToXYZ(l)) = %v, want %v", got, want)
+	}
+}
+
+func TestLabLChRoundTrip(t *testing.T) {
+	want := Lab{L: 40, A: 20, B: -15}
+
+	got := LChToLab(LabToLCh(want))
+
+	if !almostEqual(got.L, want.L, 1e-9) || !almostEqual(got.A, want.A, 1e-9) || !almostEqual(got.B, want.B, 1e-9) {
+		t.Errorf("LChToLab(LabToLCh(l)) = %v, want %v", got, want)
+	}
+}
+
+func TestRGBHSLRoundTrip(t *testing.T) {
+	want := color.RGBA{R: 12, G: 200, B: 90, A: 255}
+
+	got := HSLToRGB(RGBToHSL(want))
+	r, g, b, _ := got.RGBA()
+	wr, wg, wb, _ := want.RGBA()
+
+	// 8-bit quantization through the HSL round trip can be off by a rounding
+	// unit, so allow a small tolerance rather than requiring bit-exactness.
+	const tol = 0x0101 // one 8-bit step, scaled to color.RGBA's 16-bit range
+	if diff(r, wr) > tol || diff(g, wg) > tol || diff(b, wb) > tol {
+		t.Errorf("HSLToRGB(RGBToHSL(c)) = %v, want %v", got, want)
+	}
+}
+
+func TestRGBToHSLGrayHasNoHueOrSaturation(t *testing.T) {
+	hsl := RGBToHSL(color.Gray{Y: 128})
+
+	if hsl.H != 0 {
+		t.Errorf("RGBToHSL(gray).H = %v, want 0", hsl.H)
+	}
+	if hsl.S != 0 {
+		t.Errorf("RGBToHSL(gray).S = %v, want 0", hsl.S)
+	}
+}
+
+func TestLinearRGBXYZRoundTrip(t *testing.T) {
+	wantR, wantG, wantB := 0.25, 0.5, 0.75
+
+	v := LinearToXYZ(wantR, wantG, wantB)
+	gotR, gotG, gotB := XYZToLinear(v)
+
+	if !almostEqual(gotR, wantR, 1e-9) || !almostEqual(gotG, wantG, 1e-9) || !almostEqual(gotB, wantB, 1e-9) {
+		t.Errorf("XYZToLinear(LinearToXYZ(r,g,b)) = (%v,%v,%v), want (%v,%v,%v)", gotR, gotG, gotB, wantR, wantG, wantB)
+	}
+}
+
+func TestLabModelConvert(t *testing.T) {
+	c := color.RGBA{R: 80, G: 120, B: 160, A: 255}
+
+	want := toLAB(c)
+	got := LabModel.Convert(c)
+
+	l, ok := got.(Lab)
+	if !ok {
+		t.Fatalf("LabModel.Convert returned %T, want Lab", got)
+	}
+	if l != want {
+		t.Errorf("LabModel.Convert(c) = %v, want %v", l, want)
+	}
+}
+
+func diff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
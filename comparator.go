@@ -0,0 +1,152 @@
+package ciede2000
+
+import (
+	"image/color"
+	"math"
+)
+
+// Comparator computes a color-difference metric between two colors. The
+// returned value is formula-specific: CIEDE2000 and CIE94 target a perceptual
+// "just noticeable difference" of roughly 1.0, while CIE76 is a plain
+// Euclidean distance in L*a*b* and has no such guarantee.
+type Comparator interface {
+	Compare(c1, c2 color.Color) float64
+}
+
+// cie76 implements the original (1976) CIE L*a*b* color difference, the
+// Euclidean distance between two colors in L*a*b* space.
+type cie76 struct{}
+
+// CIE76 is a Comparator implementing the plain Euclidean distance in L*a*b*.
+// It predates CIE94 and CIEDE2000 and is cheap but perceptually non-uniform.
+var CIE76 Comparator = cie76{}
+
+func (cie76) Compare(c1, c2 color.Color) float64 {
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+
+	dl := l1.L - l2.L
+	da := l1.A - l2.A
+	db := l1.B - l2.B
+
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// cie94 implements the CIE94 color difference, a refinement of CIE76 that
+// weights the chroma and hue components relative to the first color.
+type cie94 struct {
+	kL, k1, k2 float64
+}
+
+// GraphicArts94 is the CIE94 Comparator tuned for graphic-arts applications
+// (kL=1, K1=0.045, K2=0.015).
+var GraphicArts94 Comparator = cie94{kL: 1, k1: 0.045, k2: 0.015}
+
+// Textiles94 is the CIE94 Comparator tuned for textile applications
+// (kL=2, K1=0.048, K2=0.014).
+var Textiles94 Comparator = cie94{kL: 2, k1: 0.048, k2: 0.014}
+
+func (p cie94) Compare(c1, c2 color.Color) float64 {
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+
+	c1Star := math.Sqrt(l1.A*l1.A + l1.B*l1.B)
+	c2Star := math.Sqrt(l2.A*l2.A + l2.B*l2.B)
+
+	deltaL := l1.L - l2.L
+	deltaC := c1Star - c2Star
+
+	deltaA := l1.A - l2.A
+	deltaB := l1.B - l2.B
+	deltaHSquared := deltaA*deltaA + deltaB*deltaB - deltaC*deltaC
+	if deltaHSquared < 0 {
+		deltaHSquared = 0
+	}
+	deltaH := math.Sqrt(deltaHSquared)
+
+	const sL = 1.0
+	sC := 1 + p.k1*c1Star
+	sH := 1 + p.k2*c1Star
+
+	dl := deltaL / (p.kL * sL)
+	dc := deltaC / sC
+	dh := deltaH / sH
+
+	return math.Sqrt(dl*dl + dc*dc + dh*dh)
+}
+
+// cmc implements the CMC(l:c) color difference developed by the Color
+// Measurement Committee of the Society of Dyers and Colourists.
+type cmc struct {
+	l, c float64
+}
+
+// NewCMC returns a Comparator implementing CMC(l:c). Typical values are
+// l=2, c=1 for acceptability and l=1, c=1 for perceptibility.
+func NewCMC(l, c float64) Comparator {
+	return cmc{l: l, c: c}
+}
+
+func (p cmc) Compare(c1, c2 color.Color) float64 {
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+
+	c1Star := math.Sqrt(l1.A*l1.A + l1.B*l1.B)
+	c2Star := math.Sqrt(l2.A*l2.A + l2.B*l2.B)
+
+	deltaL := l1.L - l2.L
+	deltaC := c1Star - c2Star
+
+	deltaA := l1.A - l2.A
+	deltaB := l1.B - l2.B
+	deltaHSquared := deltaA*deltaA + deltaB*deltaB - deltaC*deltaC
+	if deltaHSquared < 0 {
+		deltaHSquared = 0
+	}
+	deltaH := math.Sqrt(deltaHSquared)
+
+	var sL float64
+	if l1.L < 16 {
+		sL = 0.511
+	} else {
+		sL = (0.040975 * l1.L) / (1 + 0.01765*l1.L)
+	}
+
+	sC := (0.0638*c1Star)/(1+0.0131*c1Star) + 0.638
+
+	h1 := math.Atan2(l1.B, l1.A)
+	if h1 < 0 {
+		h1 += 2 * math.Pi
+	}
+	h1Deg := h1 * 180 / math.Pi
+
+	var f float64
+	c1Star4 := c1Star * c1Star * c1Star * c1Star
+	f = math.Sqrt(c1Star4 / (c1Star4 + 1900))
+
+	var t float64
+	if h1Deg >= 164 && h1Deg <= 345 {
+		t = 0.56 + math.Abs(0.2*math.Cos((h1Deg+168)*math.Pi/180))
+	} else {
+		t = 0.36 + math.Abs(0.4*math.Cos((h1Deg+35)*math.Pi/180))
+	}
+
+	sH := sC * (f*t + 1 - f)
+
+	dl := deltaL / (p.l * sL)
+	dc := deltaC / (p.c * sC)
+	dh := deltaH / sH
+
+	return math.Sqrt(dl*dl + dc*dc + dh*dh)
+}
+
+// ciede2000Comparator adapts Distance to the Comparator interface.
+type ciede2000Comparator struct{}
+
+// CIEDE2000 is the Comparator backed by Distance, the CIE's current (2000)
+// recommendation and the most perceptually uniform of the four formulas.
+var CIEDE2000 Comparator = ciede2000Comparator{}
+
+func (ciede2000Comparator) Compare(c1, c2 color.Color) float64 {
+	return Distance(c1, c2)
+}
@@ -0,0 +1,100 @@
+package ciede2000
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+// For a neutral (achromatic) pair of grays, C* and H* are zero up to
+// floating-point rounding for both CIE94 and CMC, so each formula collapses
+// to a lightness-only term we
+// can check independently of the implementation's own chroma/hue handling.
+func TestCIE94LightnessOnly(t *testing.T) {
+	c1 := color.Gray{Y: 60}
+	c2 := color.Gray{Y: 180}
+
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+	const eps = 1e-4
+	if math.Abs(l1.A) > eps || math.Abs(l1.B) > eps || math.Abs(l2.A) > eps || math.Abs(l2.B) > eps {
+		t.Fatalf("expected (near-)achromatic grays, got l1=%v l2=%v", l1, l2)
+	}
+
+	wantGraphicArts := math.Abs(l1.L - l2.L)
+	if got := GraphicArts94.Compare(c1, c2); !almostEqual(got, wantGraphicArts, 1e-4) {
+		t.Errorf("GraphicArts94.Compare = %v, want %v", got, wantGraphicArts)
+	}
+
+	wantTextiles := math.Abs(l1.L-l2.L) / 2
+	if got := Textiles94.Compare(c1, c2); !almostEqual(got, wantTextiles, 1e-4) {
+		t.Errorf("Textiles94.Compare = %v, want %v", got, wantTextiles)
+	}
+}
+
+func TestCMCLightnessOnly(t *testing.T) {
+	c1 := color.Gray{Y: 60}
+	c2 := color.Gray{Y: 180}
+
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+
+	var sL float64
+	if l1.L < 16 {
+		sL = 0.511
+	} else {
+		sL = (0.040975 * l1.L) / (1 + 0.01765*l1.L)
+	}
+
+	acceptability := NewCMC(2, 1)
+	want := math.Abs(l1.L-l2.L) / (2 * sL)
+	if got := acceptability.Compare(c1, c2); !almostEqual(got, want, 1e-4) {
+		t.Errorf("CMC(2:1).Compare = %v, want %v", got, want)
+	}
+
+	perceptibility := NewCMC(1, 1)
+	want = math.Abs(l1.L-l2.L) / (1 * sL)
+	if got := perceptibility.Compare(c1, c2); !almostEqual(got, want, 1e-4) {
+		t.Errorf("CMC(1:1).Compare = %v, want %v", got, want)
+	}
+}
+
+func TestCIE76IsEuclideanLab(t *testing.T) {
+	c1 := color.RGBA{R: 10, G: 200, B: 50, A: 255}
+	c2 := color.RGBA{R: 220, G: 30, B: 150, A: 255}
+
+	l1 := toLAB(c1)
+	l2 := toLAB(c2)
+
+	want := math.Sqrt((l1.L-l2.L)*(l1.L-l2.L) + (l1.A-l2.A)*(l1.A-l2.A) + (l1.B-l2.B)*(l1.B-l2.B))
+	if got := CIE76.Compare(c1, c2); !almostEqual(got, want, 1e-9) {
+		t.Errorf("CIE76.Compare = %v, want %v", got, want)
+	}
+}
+
+func TestCIEDE2000ComparatorMatchesDistance(t *testing.T) {
+	c1 := color.RGBA{R: 10, G: 200, B: 50, A: 255}
+	c2 := color.RGBA{R: 220, G: 30, B: 150, A: 255}
+
+	want := Distance(c1, c2)
+	if got := CIEDE2000.Compare(c1, c2); got != want {
+		t.Errorf("CIEDE2000.Compare = %v, want %v (Distance)", got, want)
+	}
+}
+
+func TestComparatorsAgreeColorIsSelf(t *testing.T) {
+	c := color.RGBA{R: 80, G: 120, B: 160, A: 255}
+
+	comparators := map[string]Comparator{
+		"CIE76":     CIE76,
+		"CIE94":     GraphicArts94,
+		"CMC(2:1)":  NewCMC(2, 1),
+		"CIEDE2000": CIEDE2000,
+	}
+
+	for name, cmp := range comparators {
+		if got := cmp.Compare(c, c); !almostEqual(got, 0, 1e-9) {
+			t.Errorf("%s.Compare(c, c) = %v, want 0", name, got)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package ciede2000
+
+import (
+	"image/color"
+	"math"
+)
+
+// WhitePoint is a CIE reference white expressed in XYZ (Y normalized to
+// 100). The zero value is not a valid white point.
+type WhitePoint struct {
+	X, Y, Z float64
+}
+
+// Standard illuminants under the CIE 1931 2° observer.
+var (
+	D50 = WhitePoint{X: 96.422, Y: 100.000, Z: 82.521}
+	D55 = WhitePoint{X: 95.682, Y: 100.000, Z: 92.149}
+	D65 = WhitePoint{X: 95.047, Y: 100.000, Z: 108.883}
+	D75 = WhitePoint{X: 94.972, Y: 100.000, Z: 122.638}
+	A   = WhitePoint{X: 109.850, Y: 100.000, Z: 35.585}
+	C   = WhitePoint{X: 98.074, Y: 100.000, Z: 118.232}
+	F2  = WhitePoint{X: 99.187, Y: 100.000, Z: 67.395}
+	F7  = WhitePoint{X: 95.044, Y: 100.000, Z: 108.755}
+	F11 = WhitePoint{X: 100.966, Y: 100.000, Z: 64.370}
+)
+
+// Standard illuminants under the CIE 1964 10° supplementary observer.
+var (
+	D50_10 = WhitePoint{X: 96.720, Y: 100.000, Z: 81.427}
+	D55_10 = WhitePoint{X: 95.799, Y: 100.000, Z: 90.926}
+	D65_10 = WhitePoint{X: 94.811, Y: 100.000, Z: 107.304}
+	D75_10 = WhitePoint{X: 94.416, Y: 100.000, Z: 120.641}
+	A_10   = WhitePoint{X: 111.144, Y: 100.000, Z: 35.200}
+	C_10   = WhitePoint{X: 97.285, Y: 100.000, Z: 116.145}
+	F2_10  = WhitePoint{X: 103.280, Y: 100.000, Z: 69.026}
+	F7_10  = WhitePoint{X: 95.792, Y: 100.000, Z: 107.687}
+	F11_10 = WhitePoint{X: 103.866, Y: 100.000, Z: 65.627}
+)
+
+// RGBWorkingSpace describes an RGB color space: the transfer function used
+// to linearize/re-encode channel values, and the 3x3 matrix that converts
+// linear RGB into XYZ relative to the space's own reference white.
+type RGBWorkingSpace struct {
+	Name       string
+	WhitePoint WhitePoint
+	ToLinear   func(float64) float64
+	FromLinear func(float64) float64
+	Matrix     [3][3]float64
+}
+
+func srgbCompand(v float64) float64 {
+	if v > 0.04045 {
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return v / 12.92
+}
+
+func srgbInvCompand(v float64) float64 {
+	if v > 0.0031308 {
+		return 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return v * 12.92
+}
+
+func gammaCompand(gamma float64) func(float64) float64 {
+	return func(v float64) float64 {
+		return math.Pow(v, gamma)
+	}
+}
+
+func gammaInvCompand(gamma float64) func(float64) float64 {
+	return func(v float64) float64 {
+		return math.Pow(v, 1/gamma)
+	}
+}
+
+func identity(v float64) float64 { return v }
+
+// SRGB is the sRGB working space (IEC 61966-2-1), referenced to D65. This is
+// the space assumed by Distance and by color.Color values from the standard
+// library.
+var SRGB = RGBWorkingSpace{
+	Name:       "sRGB",
+	WhitePoint: D65,
+	ToLinear:   srgbCompand,
+	FromLinear: srgbInvCompand,
+	Matrix: [3][3]float64{
+		{0.4124564, 0.3575761, 0.1804375},
+		{0.2126729, 0.7151522, 0.0721750},
+		{0.0193339, 0.1191920, 0.9503041},
+	},
+}
+
+// AdobeRGB is the Adobe RGB (1998) working space, referenced to D65.
+var AdobeRGB = RGBWorkingSpace{
+	Name:       "Adobe RGB (1998)",
+	WhitePoint: D65,
+	ToLinear:   gammaCompand(2.2),
+	FromLinear: gammaInvCompand(2.2),
+	Matrix: [3][3]float64{
+		{0.5767309, 0.1855540, 0.1881852},
+		{0.2973769, 0.6273491, 0.0752741},
+		{0.0270343, 0.0706872, 0.9911085},
+	},
+}
+
+// ProPhotoRGB is the ProPhoto RGB working space, referenced to D50.
+var ProPhotoRGB = RGBWorkingSpace{
+	Name:       "ProPhoto RGB",
+	WhitePoint: D50,
+	ToLinear:   gammaCompand(1.8),
+	FromLinear: gammaInvCompand(1.8),
+	Matrix: [3][3]float64{
+		{0.7976749, 0.1351917, 0.0313534},
+		{0.2880402, 0.7118741, 0.0000857},
+		{0.0000000, 0.0000000, 0.8252100},
+	},
+}
+
+// Rec2020 is the ITU-R BT.2020 working space, referenced to D65.
+var Rec2020 = RGBWorkingSpace{
+	Name:       "Rec. 2020",
+	WhitePoint: D65,
+	ToLinear:   gammaCompand(2.4),
+	FromLinear: gammaInvCompand(2.4),
+	Matrix: [3][3]float64{
+		{0.6369580, 0.1446169, 0.1688810},
+		{0.2627002, 0.6779981, 0.0593017},
+		{0.0000000, 0.0280727, 1.0609851},
+	},
+}
+
+// LinearRGB is the sRGB primaries with no transfer curve applied, useful
+// when input samples are already linear.
+var LinearRGB = RGBWorkingSpace{
+	Name:       "Linear RGB",
+	WhitePoint: D65,
+	ToLinear:   identity,
+	FromLinear: identity,
+	Matrix:     SRGB.Matrix,
+}
+
+// bradford is the Bradford cone-response matrix used for chromatic
+// adaptation between white points, and its inverse.
+var bradford = [3][3]float64{
+	{0.8951000, 0.2664000, -0.1614000},
+	{-0.7502000, 1.7135000, 0.0367000},
+	{0.0389000, -0.0685000, 1.0296000},
+}
+
+var bradfordInv = [3][3]float64{
+	{0.9869929, -0.1470543, 0.1599627},
+	{0.4323053, 0.5183603, 0.0492912},
+	{-0.0085287, 0.0400428, 0.9684867},
+}
+
+func mulMatVec(m [3][3]float64, v [3]float64) [3]float64 {
+	return [3]float64{
+		m[0][0]*v[0] + m[0][1]*v[1] + m[0][2]*v[2],
+		m[1][0]*v[0] + m[1][1]*v[1] + m[1][2]*v[2],
+		m[2][0]*v[0] + m[2][1]*v[1] + m[2][2]*v[2],
+	}
+}
+
+// bradfordAdapt chromatically adapts an XYZ value from the "from" white
+// point to the "to" white point using the Bradford transform.
+func bradfordAdapt(v XYZ, from, to WhitePoint) XYZ {
+	src := mulMatVec(bradford, [3]float64{from.X, from.Y, from.Z})
+	dst := mulMatVec(bradford, [3]float64{to.X, to.Y, to.Z})
+
+	cone := mulMatVec(bradford, [3]float64{v.X, v.Y, v.Z})
+	cone = [3]float64{
+		cone[0] * dst[0] / src[0],
+		cone[1] * dst[1] / src[1],
+		cone[2] * dst[2] / src[2],
+	}
+
+	adapted := mulMatVec(bradfordInv, cone)
+	return XYZ{X: adapted[0], Y: adapted[1], Z: adapted[2]}
+}
+
+// Converter binds an RGB working space and a target white point together so
+// colors can be converted to L*a*b* under illuminants and observers other
+// than the library's default sRGB/D65 assumption. Colors are chromatically
+// adapted from the working space's native white point to WhitePoint via the
+// Bradford transform.
+type Converter struct {
+	Space      RGBWorkingSpace
+	WhitePoint WhitePoint
+}
+
+// NewConverter returns a Converter for the given working space and target
+// white point.
+func NewConverter(space RGBWorkingSpace, wp WhitePoint) Converter {
+	return Converter{Space: space, WhitePoint: wp}
+}
+
+func (conv Converter) toXYZ(c color.Color) XYZ {
+	sR, sG, sB, _ := c.RGBA()
+
+	r := conv.Space.ToLinear(float64(sR) / 65535.0)
+	g := conv.Space.ToLinear(float64(sG) / 65535.0)
+	b := conv.Space.ToLinear(float64(sB) / 65535.0)
+
+	m := conv.Space.Matrix
+	v := mulMatVec(m, [3]float64{r * 100, g * 100, b * 100})
+	result := XYZ{X: v[0], Y: v[1], Z: v[2]}
+
+	if conv.WhitePoint != conv.Space.WhitePoint {
+		result = bradfordAdapt(result, conv.Space.WhitePoint, conv.WhitePoint)
+	}
+
+	return result
+}
+
+func (conv Converter) toLab(c color.Color) Lab {
+	return XYZToLab(conv.toXYZ(c), conv.WhitePoint)
+}
+
+// NewDistance returns a CIEDE2000 distance function that converts colors
+// through the given RGB working space and white point before comparing
+// them, instead of assuming sRGB/D65 as Distance does. Reference conditions
+// (kL=kC=kH=1) are used; see DistanceWithParams for parametric weighting.
+func NewDistance(space RGBWorkingSpace, wp WhitePoint) func(c1, c2 color.Color) float64 {
+	conv := NewConverter(space, wp)
+	params := GraphicArtsParams()
+
+	return func(c1, c2 color.Color) float64 {
+		l1 := conv.toLab(c1)
+		l2 := conv.toLab(c2)
+
+		return deltaE2000(l1, l2, params)
+	}
+}
@@ -0,0 +1,51 @@
+package ciede2000
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestBradfordAdaptRoundTrip(t *testing.T) {
+	v := XYZ{X: 41.24, Y: 21.26, Z: 1.93}
+
+	adapted := bradfordAdapt(v, D65, D50)
+	back := bradfordAdapt(adapted, D50, D65)
+
+	if !almostEqual(back.X, v.X, 1e-3) || !almostEqual(back.Y, v.Y, 1e-3) || !almostEqual(back.Z, v.Z, 1e-3) {
+		t.Errorf("bradfordAdapt round-trip D65->D50->D65 = %v, want %v", back, v)
+	}
+}
+
+func TestBradfordAdaptNoOpForSameWhitePoint(t *testing.T) {
+	v := XYZ{X: 41.24, Y: 21.26, Z: 1.93}
+
+	got := bradfordAdapt(v, D65, D65)
+	if !almostEqual(got.X, v.X, 1e-3) || !almostEqual(got.Y, v.Y, 1e-3) || !almostEqual(got.Z, v.Z, 1e-3) {
+		t.Errorf("bradfordAdapt(v, D65, D65) = %v, want %v unchanged", got, v)
+	}
+}
+
+func TestNewDistanceMatchesDistanceForSRGBD65(t *testing.T) {
+	c1 := color.RGBA{R: 10, G: 200, B: 50, A: 255}
+	c2 := color.RGBA{R: 220, G: 30, B: 150, A: 255}
+
+	dist := NewDistance(SRGB, D65)
+
+	want := Distance(c1, c2)
+	if got := dist(c1, c2); !almostEqual(got, want, 1e-9) {
+		t.Errorf("NewDistance(SRGB, D65)(c1, c2) = %v, want %v (Distance)", got, want)
+	}
+}
+
+func TestNewDistanceAdaptsAcrossWhitePoints(t *testing.T) {
+	c1 := color.RGBA{R: 10, G: 200, B: 50, A: 255}
+	c2 := color.RGBA{R: 220, G: 30, B: 150, A: 255}
+
+	d65 := NewDistance(SRGB, D65)
+	d50 := NewDistance(SRGB, D50)
+
+	if math.Abs(d65(c1, c2)-d50(c1, c2)) < 1e-6 {
+		t.Errorf("expected NewDistance under D65 and D50 to diverge for a chromatically adapted pair")
+	}
+}
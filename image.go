@@ -0,0 +1,229 @@
+package ciede2000
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ErrMismatchedBounds is returned by DeltaEMap when the two images do not
+// share the same bounds.
+var ErrMismatchedBounds = errors.New("ciede2000: images have mismatched bounds")
+
+// ErrEmptyImage is returned by MeanDeltaE, MaxDeltaE, and PercentileDeltaE
+// when the (matching) bounds of a and b have zero area, since no summary
+// statistic can be computed over zero pixels.
+var ErrEmptyImage = errors.New("ciede2000: image has zero area")
+
+// deltaEScale maps a ΔE00 value (unbounded in theory, but rarely seen above
+// 100 in practice) onto the full range of a 16-bit gray channel so the
+// result can be saved as a normal image.
+const deltaEScale = 65535.0 / 100.0
+
+// DeltaEMap computes the per-pixel CIEDE2000 difference between a and b and
+// returns it as a 16-bit grayscale image, scaled so a ΔE of 100 maps to
+// 65535. a and b must share the same bounds.
+func DeltaEMap(a, b image.Image) (*image.Gray16, error) {
+	if a.Bounds() != b.Bounds() {
+		return nil, ErrMismatchedBounds
+	}
+
+	bounds := a.Bounds()
+	out := image.NewGray16(bounds)
+
+	forEachStrip(bounds, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				d := Distance(a.At(x, y), b.At(x, y))
+				out.SetGray16(x, y, color.Gray16{Y: clampUint16(d * deltaEScale)})
+			}
+		}
+	})
+
+	return out, nil
+}
+
+// MeanDeltaE returns the average per-pixel CIEDE2000 difference between a
+// and b.
+func MeanDeltaE(a, b image.Image) (float64, error) {
+	vals, err := deltaEValues(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+
+	return sum / float64(len(vals)), nil
+}
+
+// MaxDeltaE returns the largest per-pixel CIEDE2000 difference between a and
+// b.
+func MaxDeltaE(a, b image.Image) (float64, error) {
+	vals, err := deltaEValues(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	max := 0.0
+	for _, v := range vals {
+		if v > max {
+			max = v
+		}
+	}
+
+	return max, nil
+}
+
+// PercentileDeltaE returns the CIEDE2000 difference at the given percentile
+// (0-100) of per-pixel differences between a and b.
+func PercentileDeltaE(a, b image.Image, percentile float64) (float64, error) {
+	vals, err := deltaEValues(a, b)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Float64s(vals)
+
+	idx := int(math.Ceil(percentile/100*float64(len(vals)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(vals) {
+		idx = len(vals) - 1
+	}
+
+	return vals[idx], nil
+}
+
+func deltaEValues(a, b image.Image) ([]float64, error) {
+	if a.Bounds() != b.Bounds() {
+		return nil, ErrMismatchedBounds
+	}
+
+	bounds := a.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return nil, ErrEmptyImage
+	}
+
+	vals := make([]float64, bounds.Dx()*bounds.Dy())
+
+	forEachStrip(bounds, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			row := (y - bounds.Min.Y) * bounds.Dx()
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				vals[row+(x-bounds.Min.X)] = Distance(a.At(x, y), b.At(x, y))
+			}
+		}
+	})
+
+	return vals, nil
+}
+
+// forEachStrip partitions bounds into horizontal strips, one per available
+// CPU, and runs fn over each strip concurrently.
+func forEachStrip(bounds image.Rectangle, fn func(y0, y1 int)) {
+	height := bounds.Dy()
+	workers := runtime.GOMAXPROCS(0)
+	if workers > height {
+		workers = height
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	stripHeight := (height + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for y0 := bounds.Min.Y; y0 < bounds.Max.Y; y0 += stripHeight {
+		y1 := y0 + stripHeight
+		if y1 > bounds.Max.Y {
+			y1 = bounds.Max.Y
+		}
+
+		wg.Add(1)
+		go func(y0, y1 int) {
+			defer wg.Done()
+			fn(y0, y1)
+		}(y0, y1)
+	}
+	wg.Wait()
+}
+
+func clampUint16(v float64) uint16 {
+	if v < 0 {
+		return 0
+	}
+	if v > 65535 {
+		return 65535
+	}
+	return uint16(v)
+}
+
+// labPalette caches the L*a*b* conversion of each color in a palette so
+// repeated nearest-color lookups don't reconvert it.
+type labPalette struct {
+	colors []color.Color
+	labs   []Lab
+}
+
+func newLabPalette(palette []color.Color) labPalette {
+	labs := make([]Lab, len(palette))
+	for i, c := range palette {
+		labs[i] = toLAB(c)
+	}
+	return labPalette{colors: palette, labs: labs}
+}
+
+func (p labPalette) nearestIndex(l Lab) int {
+	best := 0
+	bestDist := math.Inf(1)
+
+	for i, pl := range p.labs {
+		d := deltaE2000(l, pl, graphicArtsParams)
+		if d < bestDist {
+			bestDist = d
+			best = i
+		}
+	}
+
+	return best
+}
+
+var graphicArtsParams = GraphicArtsParams()
+
+// NearestPaletteIndex returns the index into palette whose color is
+// perceptually closest to c under CIEDE2000, unlike color.Palette.Index,
+// which measures Euclidean distance in sRGB.
+func NearestPaletteIndex(c color.Color, palette []color.Color) int {
+	return newLabPalette(palette).nearestIndex(toLAB(c))
+}
+
+// QuantizeToPalette maps every pixel of img to the perceptually nearest
+// color in palette under CIEDE2000, producing a paletted image. It is a
+// perceptual alternative to (color.Palette).Convert.
+func QuantizeToPalette(img image.Image, palette color.Palette) *image.Paletted {
+	bounds := img.Bounds()
+	out := image.NewPaletted(bounds, palette)
+
+	colors := make([]color.Color, len(palette))
+	copy(colors, palette)
+	lp := newLabPalette(colors)
+
+	forEachStrip(bounds, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				idx := lp.nearestIndex(toLAB(img.At(x, y)))
+				out.SetColorIndex(x, y, uint8(idx))
+			}
+		}
+	})
+
+	return out
+}
@@ -0,0 +1,119 @@
+package ciede2000
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDeltaEMapIdentical(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.RGBA{R: 100, G: 150, B: 200, A: 255})
+		}
+	}
+
+	m, err := DeltaEMap(img, img)
+	if err != nil {
+		t.Fatalf("DeltaEMap: %v", err)
+	}
+
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			if got := m.Gray16At(x, y).Y; got != 0 {
+				t.Errorf("DeltaEMap(img, img).At(%d,%d) = %d, want 0", x, y, got)
+			}
+		}
+	}
+}
+
+func TestDeltaEMapMismatchedBounds(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	b := image.NewRGBA(image.Rect(0, 0, 3, 3))
+
+	if _, err := DeltaEMap(a, b); err != ErrMismatchedBounds {
+		t.Errorf("DeltaEMap with mismatched bounds = %v, want ErrMismatchedBounds", err)
+	}
+}
+
+func TestSummaryStatsOnEmptyImage(t *testing.T) {
+	empty := image.NewRGBA(image.Rect(0, 0, 0, 0))
+
+	if _, err := MeanDeltaE(empty, empty); err != ErrEmptyImage {
+		t.Errorf("MeanDeltaE on empty image = %v, want ErrEmptyImage", err)
+	}
+	if _, err := MaxDeltaE(empty, empty); err != ErrEmptyImage {
+		t.Errorf("MaxDeltaE on empty image = %v, want ErrEmptyImage", err)
+	}
+	if _, err := PercentileDeltaE(empty, empty, 50); err != ErrEmptyImage {
+		t.Errorf("PercentileDeltaE on empty image = %v, want ErrEmptyImage", err)
+	}
+}
+
+func TestSummaryStats(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	a.Set(0, 0, color.RGBA{R: 0, G: 0, B: 0, A: 255})
+	a.Set(1, 0, color.RGBA{R: 0, G: 0, B: 0, A: 255})
+
+	b := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	b.Set(0, 0, color.RGBA{R: 0, G: 0, B: 0, A: 255}) // identical -> ΔE 0
+	b.Set(1, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	want := Distance(a.At(1, 0), b.At(1, 0))
+
+	mean, err := MeanDeltaE(a, b)
+	if err != nil {
+		t.Fatalf("MeanDeltaE: %v", err)
+	}
+	if wantMean := want / 2; !almostEqual(mean, wantMean, 1e-9) {
+		t.Errorf("MeanDeltaE = %v, want %v", mean, wantMean)
+	}
+
+	max, err := MaxDeltaE(a, b)
+	if err != nil {
+		t.Fatalf("MaxDeltaE: %v", err)
+	}
+	if !almostEqual(max, want, 1e-9) {
+		t.Errorf("MaxDeltaE = %v, want %v", max, want)
+	}
+
+	p100, err := PercentileDeltaE(a, b, 100)
+	if err != nil {
+		t.Fatalf("PercentileDeltaE: %v", err)
+	}
+	if !almostEqual(p100, want, 1e-9) {
+		t.Errorf("PercentileDeltaE(100) = %v, want %v", p100, want)
+	}
+}
+
+func TestNearestPaletteIndexAndQuantize(t *testing.T) {
+	palette := []color.Color{
+		color.RGBA{R: 0, G: 0, B: 0, A: 255},
+		color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		color.RGBA{R: 255, G: 0, B: 0, A: 255},
+	}
+
+	if idx := NearestPaletteIndex(color.RGBA{R: 250, G: 10, B: 10, A: 255}, palette); idx != 2 {
+		t.Errorf("NearestPaletteIndex(near-red) = %d, want 2", idx)
+	}
+	if idx := NearestPaletteIndex(color.RGBA{R: 10, G: 10, B: 10, A: 255}, palette); idx != 0 {
+		t.Errorf("NearestPaletteIndex(near-black) = %d, want 0", idx)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.RGBA{R: 245, G: 245, B: 245, A: 255})
+
+	quantized := QuantizeToPalette(img, color.Palette(palette))
+	if got := quantized.ColorIndexAt(0, 0); got != 1 {
+		t.Errorf("QuantizeToPalette near-white = %d, want 1", got)
+	}
+}
+
+func almostEqual(a, b, tolerance float64) bool {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	return d <= tolerance
+}
@@ -0,0 +1,24 @@
+package ciede2000
+
+// Params carries the parametric weighting factors kL, kC, kH used by
+// CIEDE2000 to account for viewing conditions that depart from the CIE
+// reference conditions (kL=kC=kH=1). Unsupplied fields default to their
+// zero value, so construct Params through GraphicArtsParams or
+// TextilesParams rather than a bare literal.
+type Params struct {
+	KL, KC, KH float64
+}
+
+// GraphicArtsParams returns the CIE reference-condition weights (kL=kC=kH=1),
+// appropriate for the graphic-arts viewing conditions CIEDE2000 was
+// developed under.
+func GraphicArtsParams() Params {
+	return Params{KL: 1, KC: 1, KH: 1}
+}
+
+// TextilesParams returns the weights recommended by the CIE for textile
+// applications (kL=2, kC=kH=1), which tolerate larger lightness differences
+// than graphic arts before they're perceived as a mismatch.
+func TextilesParams() Params {
+	return Params{KL: 2, KC: 1, KH: 1}
+}